@@ -2,6 +2,9 @@ package controllersv1
 
 import (
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -11,6 +14,7 @@ import (
 	"github.com/sirupsen/logrus"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
@@ -27,6 +31,33 @@ type kubeController struct {
 
 var KubeController = kubeController{}
 
+// wsConn serializes every write to a kube-events websocket connection.
+// gorilla/websocket allows at most one concurrent writer per connection, but
+// this handler writes to it from several independent goroutines (the
+// informer callbacks via send(), the heartbeat ticker, and the one-off
+// history replay), so all of them must go through the same mutex instead of
+// calling conn.WriteJSON/WriteMessage directly.
+type wsConn struct {
+	*websocket.Conn
+	mu sync.Mutex
+}
+
+func newWsConn(conn *websocket.Conn) *wsConn {
+	return &wsConn{Conn: conn}
+}
+
+func (c *wsConn) WriteJSON(v interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.Conn.WriteJSON(v)
+}
+
+func (c *wsConn) writeError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	writeWsError(c.Conn, err)
+}
+
 func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSchema) error {
 	var err error
 
@@ -38,8 +69,10 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 	}
 	defer conn.Close()
 
+	wc := newWsConn(conn)
+
 	defer func() {
-		writeWsError(conn, err)
+		wc.writeError(err)
 	}()
 
 	cluster, err := schema.GetCluster(ctx)
@@ -52,6 +85,8 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 		return err
 	}
 
+	services.EnsureKubeEventHistoryCollector(ctx, cluster)
+
 	closeCh := make(chan struct{})
 	toClose := make(chan struct{}, 1)
 
@@ -72,9 +107,11 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 	}
 	defer doClose()
 
+	onPong := startKubeEventsHeartbeat(wc, closeCh, doClose)
+
 	go func() {
 		for {
-			_, _, err := conn.ReadMessage()
+			msgType, msg, err := conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					logrus.Errorf("ws read failed: %q", err.Error())
@@ -82,6 +119,9 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 				doClose()
 				return
 			}
+			if msgType == websocket.TextMessage && string(msg) == "pong" {
+				onPong()
+			}
 		}
 	}()
 
@@ -91,6 +131,8 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 
 	kubeNs := ctx.Query("namespace")
 	podName := ctx.Query("pod_name")
+	var eventSelectors []fields.Selector
+	var podUID string
 	if podName != "" {
 		var cliset *kubernetes.Clientset
 		cliset, _, err = services.ClusterService.GetKubeCliSet(ctx, cluster)
@@ -106,12 +148,15 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 			return err
 		}
 
+		podUID = string(pod.UID)
 		filter = func(event *corev1.Event) bool {
 			return event.InvolvedObject.Kind == consts.KubeEventResourceKindPod && event.InvolvedObject.UID == pod.UID
 		}
+		eventSelectors = append(eventSelectors, services.InvolvedObjectSelector(consts.KubeEventResourceKindPod, podUID))
+		defer services.ReleaseEventInformer(cluster, kubeNs, eventSelectors...)
 	}
 
-	eventInformer, eventLister, err := services.GetEventInformer(ctx, cluster, kubeNs)
+	eventInformer, eventLister, err := services.GetEventInformer(ctx, cluster, kubeNs, eventSelectors...)
 	if err != nil {
 		err = errors.Wrap(err, "get event informer")
 		return err
@@ -131,6 +176,8 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 		time.Sleep(time.Second * 10)
 	}
 
+	statusTracker := services.NewEventStatusTracker()
+
 	send := func() {
 		select {
 		case <-closeCh:
@@ -140,7 +187,7 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 
 		var err error
 		defer func() {
-			writeWsError(conn, err)
+			wc.writeError(err)
 			if err != nil {
 				failed()
 			}
@@ -169,10 +216,15 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 			return it.Before(&jt)
 		})
 
-		err = conn.WriteJSON(&schemasv1.WsRespSchema{
-			Type:    schemasv1.WsRespTypeSuccess,
+		diffs := statusTracker.Diff(_events)
+		if len(diffs) == 0 {
+			return
+		}
+
+		err = wc.WriteJSON(&schemasv1.WsRespSchema{
+			Type:    services.WsRespTypeEventDiff,
 			Message: "",
-			Payload: _events,
+			Payload: services.EventDiffEnvelope{Replayed: false, Diffs: diffs},
 		})
 		if err != nil {
 			err = errors.Wrap(err, "ws write json")
@@ -180,6 +232,21 @@ func (c *kubeController) GetPodKubeEvents(ctx *gin.Context, schema *GetClusterSc
 		}
 	}
 
+	statusFilter := func(status services.EventStatus) bool {
+		return true
+	}
+	if podUID != "" {
+		statusFilter = func(status services.EventStatus) bool {
+			return status.InvolvedKind == consts.KubeEventResourceKindPod && string(status.InvolvedUID) == podUID
+		}
+	}
+	replayed, err := replayKubeEventHistory(ctx, wc, cluster.Name, kubeNs, statusFilter)
+	if err != nil {
+		err = errors.Wrap(err, "replay kube event history")
+		return err
+	}
+	statusTracker.Seed(replayed)
+
 	send()
 
 	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -243,8 +310,10 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 	}
 	defer conn.Close()
 
+	wc := newWsConn(conn)
+
 	defer func() {
-		writeWsError(conn, err)
+		wc.writeError(err)
 	}()
 
 	deployment, err := schema.GetDeployment(ctx)
@@ -277,9 +346,11 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 	}
 	defer doClose()
 
+	onPong := startKubeEventsHeartbeat(wc, closeCh, doClose)
+
 	go func() {
 		for {
-			_, _, err := conn.ReadMessage()
+			msgType, msg, err := conn.ReadMessage()
 			if err != nil {
 				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
 					logrus.Errorf("ws read failed: %q", err.Error())
@@ -287,6 +358,9 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 				doClose()
 				return
 			}
+			if msgType == websocket.TextMessage && string(msg) == "pong" {
+				onPong()
+			}
 		}
 	}()
 
@@ -295,22 +369,26 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 		return err
 	}
 
+	services.EnsureKubeEventHistoryCollector(ctx, cluster)
+
 	eventFilter, err := services.KubeEventService.MakeDeploymentKubeEventFilter(ctx, deployment, nil)
 	if err != nil {
 		return err
 	}
 
-	podName := ctx.Query("pod_name")
-	if podName != "" {
-		var cliset *kubernetes.Clientset
-		cliset, _, err = services.ClusterService.GetKubeCliSet(ctx, cluster)
-		if err != nil {
-			return err
-		}
+	kubeNs := services.DeploymentService.GetKubeNamespace(deployment)
 
-		kubeNs := services.DeploymentService.GetKubeNamespace(deployment)
-		podsCli := cliset.CoreV1().Pods(kubeNs)
+	var cliset *kubernetes.Clientset
+	cliset, _, err = services.ClusterService.GetKubeCliSet(ctx, cluster)
+	if err != nil {
+		return err
+	}
+	podsCli := cliset.CoreV1().Pods(kubeNs)
 
+	podName := ctx.Query("pod_name")
+	var eventSelectors []fields.Selector
+	var podUID string
+	if podName != "" {
 		var pod *corev1.Pod
 		pod, err = podsCli.Get(ctx, podName, metav1.GetOptions{})
 		if err != nil {
@@ -322,12 +400,15 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 			return err
 		}
 
+		podUID = string(pod.UID)
 		eventFilter = func(event *corev1.Event) bool {
 			return event.InvolvedObject.Kind == consts.KubeEventResourceKindPod && event.InvolvedObject.UID == pod.UID
 		}
+		eventSelectors = append(eventSelectors, services.InvolvedObjectSelector(consts.KubeEventResourceKindPod, podUID))
+		defer services.ReleaseEventInformer(cluster, kubeNs, eventSelectors...)
 	}
 
-	eventInformer, eventLister, err := services.GetEventInformer(ctx, cluster, services.DeploymentService.GetKubeNamespace(deployment))
+	eventInformer, eventLister, err := services.GetEventInformer(ctx, cluster, kubeNs, eventSelectors...)
 	if err != nil {
 		err = errors.Wrap(err, "get event informer")
 		return err
@@ -347,7 +428,7 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 		time.Sleep(time.Second * 10)
 	}
 
-	seen := make(map[string]struct{})
+	statusTracker := services.NewEventStatusTracker()
 
 	send := func() {
 		select {
@@ -358,7 +439,7 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 
 		var err error
 		defer func() {
-			writeWsError(conn, err)
+			wc.writeError(err)
 			if err != nil {
 				failed()
 			}
@@ -378,9 +459,6 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 			if !eventFilter(event) {
 				continue
 			}
-			if _, ok := seen[event.Message]; ok {
-				continue
-			}
 			_events = append(_events, event)
 		}
 
@@ -411,19 +489,51 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 			return it.Before(jt)
 		})
 
+		diffs := statusTracker.Diff(_events)
+		if len(diffs) == 0 {
+			return
+		}
+
 		select {
 		case <-closeCh:
 			return
 		default:
 		}
 
-		err = conn.WriteJSON(&schemasv1.WsRespSchema{
-			Type:    schemasv1.WsRespTypeSuccess,
+		err = wc.WriteJSON(&schemasv1.WsRespSchema{
+			Type:    services.WsRespTypeEventDiff,
 			Message: "",
-			Payload: _events,
+			Payload: services.EventDiffEnvelope{Replayed: false, Diffs: diffs},
 		})
 	}
 
+	// KubeEventHistory doesn't persist a deployment/owner column, so replay
+	// can't narrow by the same deployment labels eventFilter matches on with
+	// a DB query. Instead it re-derives the same scoping the live stream
+	// uses: every candidate row's involved pod is fetched and checked
+	// against the deployment label, exactly like the pod_name-scoped filter
+	// above. Namespace-only scoping would leak other deployments' events
+	// that merely happen to share the namespace.
+	statusFilter := func(status services.EventStatus) bool {
+		if status.InvolvedKind != consts.KubeEventResourceKindPod {
+			return false
+		}
+		if podUID != "" {
+			return string(status.InvolvedUID) == podUID
+		}
+		pod, err := podsCli.Get(ctx, status.InvolvedName, metav1.GetOptions{})
+		if err != nil {
+			return false
+		}
+		return pod.Labels[consts.KubeLabelYataiDeployment] == deployment.Name
+	}
+	replayed, err := replayKubeEventHistory(ctx, wc, cluster.Name, kubeNs, statusFilter)
+	if err != nil {
+		err = errors.Wrap(err, "replay kube event history")
+		return err
+	}
+	statusTracker.Seed(replayed)
+
 	send()
 
 	eventInformer_.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -475,3 +585,123 @@ func (c *kubeController) GetDeploymentKubeEvents(ctx *gin.Context, schema *GetDe
 
 	return nil
 }
+
+const (
+	kubeEventsPingInterval = time.Second * 10
+	kubeEventsPongMisses   = 3
+)
+
+// kubeEventsHeartbeatTimedOut reports whether it has been long enough since
+// lastPongAt (a time.Time, as stored in the atomic via UnixNano) that the
+// connection should be considered dead, i.e. more than kubeEventsPongMisses
+// worth of kubeEventsPingInterval has elapsed without a pong.
+func kubeEventsHeartbeatTimedOut(lastPongAt int64, now time.Time) bool {
+	return now.Sub(time.Unix(0, lastPongAt)) > kubeEventsPingInterval*kubeEventsPongMisses
+}
+
+// startKubeEventsHeartbeat sends a WsRespTypePing frame on conn every
+// kubeEventsPingInterval and calls doClose if no "pong" text frame arrives
+// within kubeEventsPongMisses intervals, so a wedged browser tab gets torn
+// down deterministically instead of leaving the informer's send path writing
+// into a dead socket until failedCount finally trips maxFailed. It returns
+// the function the connection's read loop should call whenever it sees a
+// pong frame.
+func startKubeEventsHeartbeat(conn *wsConn, closeCh <-chan struct{}, doClose func()) func() {
+	var lastPongAt int64
+	atomic.StoreInt64(&lastPongAt, time.Now().UnixNano())
+
+	go func() {
+		ticker := time.NewTicker(kubeEventsPingInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-closeCh:
+				return
+			case <-ticker.C:
+			}
+
+			if kubeEventsHeartbeatTimedOut(atomic.LoadInt64(&lastPongAt), time.Now()) {
+				logrus.Warn("ws kube events heartbeat timed out, closing connection")
+				doClose()
+				return
+			}
+
+			if err := conn.WriteJSON(&schemasv1.WsRespSchema{Type: services.WsRespTypePing}); err != nil {
+				doClose()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		atomic.StoreInt64(&lastPongAt, time.Now().UnixNano())
+	}
+}
+
+// replayKubeEventHistory parses the since/limit/reasons query params off ctx
+// and, if since is present, writes matching events recorded in
+// services.KubeEventHistoryStore to conn before the caller attaches its live
+// informer handler, returning those same events so the caller can seed its
+// EventStatusTracker with them and avoid re-announcing them as newly Added
+// once the live stream starts. statusFilter narrows the replay to the same
+// scope (e.g. a single pod) as the live stream. It is a no-op (nil, nil) if
+// since is absent or no history store has been configured.
+func replayKubeEventHistory(ctx *gin.Context, conn *wsConn, clusterName, namespace string, statusFilter func(services.EventStatus) bool) ([]services.EventStatus, error) {
+	if services.KubeEventHistoryStore == nil {
+		return nil, nil
+	}
+
+	sinceStr := ctx.Query("since")
+	if sinceStr == "" {
+		return nil, nil
+	}
+
+	since, err := time.Parse(time.RFC3339, sinceStr)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse since")
+	}
+
+	limit := 0
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limit, err = strconv.Atoi(limitStr)
+		if err != nil {
+			return nil, errors.Wrap(err, "parse limit")
+		}
+	}
+
+	var reasons []string
+	if reasonsStr := ctx.Query("reasons"); reasonsStr != "" {
+		reasons = strings.Split(reasonsStr, ",")
+	}
+
+	statuses, err := services.KubeEventHistoryStore.List(ctx, clusterName, namespace, since, reasons, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "list kube event history")
+	}
+
+	replayed := make([]services.EventStatus, 0, len(statuses))
+	diffs := make([]services.EventDiff, 0, len(statuses))
+	for i := range statuses {
+		status := statuses[i]
+		if !statusFilter(status) {
+			continue
+		}
+		replayed = append(replayed, status)
+		diffs = append(diffs, services.EventDiff{Kind: services.EventDiffKindAdded, Status: &status})
+	}
+
+	if len(diffs) == 0 {
+		return nil, nil
+	}
+
+	if err := conn.WriteJSON(&schemasv1.WsRespSchema{
+		Type:    services.WsRespTypeEventDiff,
+		Message: "",
+		Payload: services.EventDiffEnvelope{Replayed: true, Diffs: diffs},
+	}); err != nil {
+		return nil, err
+	}
+
+	return replayed, nil
+}
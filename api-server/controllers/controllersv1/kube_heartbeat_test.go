@@ -0,0 +1,29 @@
+package controllersv1
+
+import (
+	"testing"
+	"time"
+)
+
+func TestKubeEventsHeartbeatTimedOut(t *testing.T) {
+	now := time.Now()
+
+	cases := []struct {
+		name       string
+		lastPongAt time.Time
+		want       bool
+	}{
+		{name: "just pinged", lastPongAt: now, want: false},
+		{name: "within allowed misses", lastPongAt: now.Add(-kubeEventsPingInterval * (kubeEventsPongMisses - 1)), want: false},
+		{name: "exactly at threshold is not yet timed out", lastPongAt: now.Add(-kubeEventsPingInterval * kubeEventsPongMisses), want: false},
+		{name: "past threshold", lastPongAt: now.Add(-kubeEventsPingInterval*kubeEventsPongMisses - time.Second), want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := kubeEventsHeartbeatTimedOut(c.lastPongAt.UnixNano(), now); got != c.want {
+				t.Errorf("kubeEventsHeartbeatTimedOut() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
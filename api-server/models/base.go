@@ -0,0 +1,10 @@
+package models
+
+import "k8s.io/apimachinery/pkg/types"
+
+// BaseModel carries the identity fields shared by every persisted Yatai
+// model.
+type BaseModel struct {
+	ID  uint      `json:"id"`
+	UID types.UID `json:"uid"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// Cluster is the persisted representation of a Kubernetes cluster Yatai
+// manages deployments on.
+type Cluster struct {
+	BaseModel
+
+	Name string `json:"name"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// KubeEventHistory is a persisted copy of a Kubernetes event, recorded by a
+// cluster-wide collector so that GetPodKubeEvents/GetDeploymentKubeEvents can
+// replay events the informer cache has since evicted. One row is kept per
+// (ClusterName, UID), where UID is the Event object's own identity, not the
+// involved object's: a single pod routinely produces several distinct events
+// (FailedScheduling, then Pulling, then BackOff, ...) that must not collide
+// into one row. InvolvedUID identifies the pod/object the event is about, for
+// scoping replay to a single pod or deployment.
+type KubeEventHistory struct {
+	BaseModel
+
+	ClusterName  string    `json:"cluster_name" gorm:"index"`
+	Namespace    string    `json:"namespace" gorm:"index"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	Reason       string    `json:"reason" gorm:"index"`
+	InvolvedKind string    `json:"involved_kind"`
+	InvolvedUID  types.UID `json:"involved_uid" gorm:"index"`
+	InvolvedName string    `json:"involved_name"`
+	Message      string    `json:"message"`
+	Count        int32     `json:"count"`
+
+	FirstSeen time.Time `json:"first_seen"`
+	LastSeen  time.Time `json:"last_seen" gorm:"index"`
+}
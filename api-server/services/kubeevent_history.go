@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/bentoml/yatai/api-server/models"
+	"github.com/bentoml/yatai/api-server/services/kubeevent_sink"
+)
+
+// kubeEventHistoryDefaultTTL is the retention PruneExpired applies when it is
+// given no positive ttl. Rows are only removed when PruneExpired runs.
+const kubeEventHistoryDefaultTTL = time.Hour * 72
+
+type kubeEventHistoryStore struct {
+	db *gorm.DB
+}
+
+// KubeEventHistoryStore is the persistent ring buffer of past kube events
+// used to replay history to a client before attaching it to the live
+// informer. It is nil until InitKubeEventHistoryStore has been called.
+var KubeEventHistoryStore *kubeEventHistoryStore
+
+// InitKubeEventHistoryStore wires the persistent event history store to db.
+// EnsureKubeEventHistoryCollector calls it once, when the first kube-events
+// websocket request is served; it is not called at process start.
+func InitKubeEventHistoryStore(db *gorm.DB) {
+	KubeEventHistoryStore = &kubeEventHistoryStore{db: db}
+}
+
+// Record upserts event into the history store, keyed by the event object's
+// own UID within clusterName. One pod commonly produces several distinct
+// events (FailedScheduling, then Pulling, then BackOff, ...) sharing the same
+// InvolvedObject.UID, so the involved object's UID cannot be the row key
+// without later events overwriting earlier ones.
+func (s *kubeEventHistoryStore) Record(ctx context.Context, clusterName string, event *corev1.Event) error {
+	row := models.KubeEventHistory{
+		ClusterName:  clusterName,
+		Namespace:    event.Namespace,
+		Name:         event.Name,
+		Type:         event.Type,
+		Reason:       event.Reason,
+		InvolvedKind: event.InvolvedObject.Kind,
+		InvolvedUID:  event.InvolvedObject.UID,
+		InvolvedName: event.InvolvedObject.Name,
+		Message:      event.Message,
+		Count:        event.Count,
+		FirstSeen:    event.FirstTimestamp.Time,
+		LastSeen:     event.LastTimestamp.Time,
+	}
+	row.UID = event.UID
+
+	err := s.db.WithContext(ctx).
+		Where(models.KubeEventHistory{ClusterName: clusterName, BaseModel: models.BaseModel{UID: row.UID}}).
+		Assign(row).
+		FirstOrCreate(&row).Error
+
+	return errors.Wrap(err, "record kube event history")
+}
+
+// List replays history recorded for clusterName/namespace since the given
+// time, most recent first, optionally restricted to reasons and capped at
+// limit (0 means unbounded).
+func (s *kubeEventHistoryStore) List(ctx context.Context, clusterName, namespace string, since time.Time, reasons []string, limit int) ([]EventStatus, error) {
+	var rows []models.KubeEventHistory
+
+	q := s.db.WithContext(ctx).
+		Where("cluster_name = ? AND namespace = ? AND last_seen >= ?", clusterName, namespace, since).
+		Order("last_seen DESC")
+
+	if len(reasons) > 0 {
+		q = q.Where("reason IN ?", reasons)
+	}
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, errors.Wrap(err, "list kube event history")
+	}
+
+	statuses := make([]EventStatus, 0, len(rows))
+	for _, row := range rows {
+		statuses = append(statuses, EventStatus{
+			Name:         row.Name,
+			UID:          row.UID,
+			Namespace:    row.Namespace,
+			Type:         row.Type,
+			Reason:       row.Reason,
+			Severity:     classifyEventReason(row.Type, row.Reason),
+			InvolvedKind: row.InvolvedKind,
+			InvolvedUID:  row.InvolvedUID,
+			InvolvedName: row.InvolvedName,
+			FirstSeen:    metav1.NewTime(row.FirstSeen),
+			LastSeen:     metav1.NewTime(row.LastSeen),
+			Count:        row.Count,
+			Message:      row.Message,
+		})
+	}
+
+	return statuses, nil
+}
+
+// PruneExpired deletes history rows whose LastSeen is older than ttl (or
+// kubeEventHistoryDefaultTTL if ttl is zero or negative). Nothing in this
+// package calls it or schedules it; rows stay until a caller runs it.
+func (s *kubeEventHistoryStore) PruneExpired(ctx context.Context, ttl time.Duration) error {
+	if ttl <= 0 {
+		ttl = kubeEventHistoryDefaultTTL
+	}
+
+	err := s.db.WithContext(ctx).
+		Where("last_seen < ?", time.Now().Add(-ttl)).
+		Delete(&models.KubeEventHistory{}).Error
+
+	return errors.Wrap(err, "prune kube event history")
+}
+
+// StartClusterWideCollector attaches an all-namespaces event informer for
+// cluster and records every event it sees into KubeEventHistoryStore, so
+// replay requests can surface events (e.g. FailedScheduling, BackOff) that
+// happened before the requesting client connected and that the informer's
+// own in-memory cache has since evicted. It also dispatches every event to
+// KubeEventSinkManager: this is the single cluster-wide collector, so it is
+// the only place sink dispatch can happen exactly once per event, regardless
+// of how many websocket clients (zero or many) happen to be watching.
+// EnsureKubeEventHistoryCollector starts it once per cluster, when the first
+// kube-events websocket for that cluster is served; it is not started at
+// boot, so events that happened before then are not recorded.
+func StartClusterWideCollector(ctx context.Context, cluster *models.Cluster) error {
+	if KubeEventHistoryStore == nil {
+		return errors.New("kube event history store not initialized")
+	}
+
+	EnsureKubeEventSinks(ctx)
+
+	eventInformer, _, err := GetEventInformer(ctx, cluster, metav1.NamespaceAll)
+	if err != nil {
+		return errors.Wrap(err, "get cluster-wide event informer")
+	}
+
+	handle := func(op kubeevent_sink.EventOp, event *corev1.Event) {
+		if err := KubeEventHistoryStore.Record(ctx, cluster.Name, event); err != nil {
+			logrus.WithError(err).Warn("failed to record kube event history")
+		}
+		KubeEventSinkManager.Dispatch(cluster.Name, op, event)
+	}
+
+	eventInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if event, ok := obj.(*corev1.Event); ok {
+				handle(kubeevent_sink.EventOpAdd, event)
+			}
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			if event, ok := newObj.(*corev1.Event); ok {
+				handle(kubeevent_sink.EventOpUpdate, event)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if event, ok := obj.(*corev1.Event); ok {
+				handle(kubeevent_sink.EventOpDelete, event)
+			}
+		},
+	})
+
+	return nil
+}
+
+var (
+	kubeEventHistoryInitOnce  sync.Once
+	kubeEventHistoryStartedMu sync.Mutex
+	kubeEventHistoryStarted   = make(map[string]struct{})
+)
+
+// EnsureKubeEventHistoryCollector makes sure KubeEventHistoryStore is
+// initialized and that a cluster-wide collector is running for cluster,
+// starting either one on demand the first time a kube-events websocket
+// handler is served for that cluster. There is no separate process-boot
+// wiring step: GetPodKubeEvents/GetDeploymentKubeEvents call this
+// themselves, so replay works as soon as the first client connects rather
+// than depending on an external initializer that might never run.
+//
+// The caller's ctx is only ever used to look up the database handle and is
+// otherwise discarded: both the store and the collector outlive any single
+// request, so starting them with the request's *gin.Context would have the
+// collector's informer callbacks recording into a context that net/http
+// cancels the moment the one viewer who happened to start it disconnects.
+func EnsureKubeEventHistoryCollector(ctx context.Context, cluster *models.Cluster) {
+	kubeEventHistoryInitOnce.Do(func() {
+		db, err := GetDatabase(ctx)
+		if err != nil {
+			logrus.WithError(err).Error("kube event history: failed to get database")
+			return
+		}
+		InitKubeEventHistoryStore(db)
+	})
+
+	if KubeEventHistoryStore == nil {
+		return
+	}
+
+	kubeEventHistoryStartedMu.Lock()
+	_, started := kubeEventHistoryStarted[cluster.Name]
+	kubeEventHistoryStarted[cluster.Name] = struct{}{}
+	kubeEventHistoryStartedMu.Unlock()
+
+	if started {
+		return
+	}
+
+	if err := StartClusterWideCollector(context.Background(), cluster); err != nil {
+		logrus.WithError(err).Errorf("kube event history: failed to start collector for cluster %s", cluster.Name)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	kubeinformers "k8s.io/client-go/informers"
+	corev1informers "k8s.io/client-go/informers/core/v1"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/bentoml/yatai/api-server/models"
+)
+
+const (
+	eventInformerResyncPeriod = time.Minute * 10
+	// eventInformerIdleTimeout is how long a field-selector-scoped event
+	// informer is kept alive with zero subscribers before it is stopped and
+	// evicted from the cache. Namespace-wide informers (no selector) are
+	// never evicted, matching the previous behaviour.
+	eventInformerIdleTimeout = time.Minute * 5
+	eventInformerIdleSweep   = time.Minute
+)
+
+type eventInformerCacheKey struct {
+	clusterName string
+	namespace   string
+	selector    string
+}
+
+type eventInformerEntry struct {
+	informer    corev1informers.EventInformer
+	lister      corev1listers.EventLister
+	stopCh      chan struct{}
+	scoped      bool
+	subscribers int
+	idleSince   time.Time
+}
+
+var (
+	eventInformerCacheMu sync.Mutex
+	eventInformerCache   = make(map[eventInformerCacheKey]*eventInformerEntry)
+)
+
+// GetEventInformer returns a shared Event informer/lister for the given
+// cluster and namespace, starting one and caching it if this is the first
+// caller to ask for it.
+//
+// When one or more field selectors are passed (e.g. to scope the informer to
+// `involvedObject.uid=<uid>`), the informer is cached and evicted separately
+// from the namespace-wide informer used when no selector is given, and is
+// stopped after eventInformerIdleTimeout once its last subscriber releases it
+// via ReleaseEventInformer.
+func GetEventInformer(ctx context.Context, cluster *models.Cluster, namespace string, selectors ...fields.Selector) (corev1informers.EventInformer, corev1listers.EventLister, error) {
+	selectorStr := mergeFieldSelectors(selectors)
+	key := eventInformerCacheKey{clusterName: cluster.Name, namespace: namespace, selector: selectorStr}
+
+	eventInformerCacheMu.Lock()
+	if entry, ok := eventInformerCache[key]; ok {
+		entry.subscribers++
+		entry.idleSince = time.Time{}
+		eventInformerCacheMu.Unlock()
+		return entry.informer, entry.lister, nil
+	}
+	eventInformerCacheMu.Unlock()
+
+	cliset, _, err := ClusterService.GetKubeCliSet(ctx, cluster)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get kube clientset")
+	}
+
+	opts := []kubeinformers.SharedInformerOption{kubeinformers.WithNamespace(namespace)}
+	if selectorStr != "" {
+		opts = append(opts, kubeinformers.WithTweakListOptions(func(lo *metav1.ListOptions) {
+			lo.FieldSelector = selectorStr
+		}))
+	}
+
+	factory := kubeinformers.NewSharedInformerFactoryWithOptions(cliset, eventInformerResyncPeriod, opts...)
+	informer := factory.Core().V1().Events()
+
+	stopCh := make(chan struct{})
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	entry := &eventInformerEntry{
+		informer:    informer,
+		lister:      informer.Lister(),
+		stopCh:      stopCh,
+		scoped:      selectorStr != "",
+		subscribers: 1,
+	}
+
+	eventInformerCacheMu.Lock()
+	eventInformerCache[key] = entry
+	eventInformerCacheMu.Unlock()
+
+	if entry.scoped {
+		go evictEventInformerWhenIdle(key)
+	}
+
+	return entry.informer, entry.lister, nil
+}
+
+// ReleaseEventInformer marks one subscriber of the informer identified by
+// (cluster, namespace, selectors) as done with it. Once a scoped informer's
+// subscriber count drops to zero, it is stopped and removed from the cache
+// after eventInformerIdleTimeout with nobody re-subscribing.
+func ReleaseEventInformer(cluster *models.Cluster, namespace string, selectors ...fields.Selector) {
+	key := eventInformerCacheKey{clusterName: cluster.Name, namespace: namespace, selector: mergeFieldSelectors(selectors)}
+
+	eventInformerCacheMu.Lock()
+	defer eventInformerCacheMu.Unlock()
+
+	entry, ok := eventInformerCache[key]
+	if !ok {
+		return
+	}
+	entry.subscribers--
+	if entry.subscribers <= 0 {
+		entry.subscribers = 0
+		entry.idleSince = time.Now()
+	}
+}
+
+func evictEventInformerWhenIdle(key eventInformerCacheKey) {
+	ticker := time.NewTicker(eventInformerIdleSweep)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		eventInformerCacheMu.Lock()
+		entry, ok := eventInformerCache[key]
+		if !ok {
+			eventInformerCacheMu.Unlock()
+			return
+		}
+		if entry.subscribers == 0 && !entry.idleSince.IsZero() && time.Since(entry.idleSince) >= eventInformerIdleTimeout {
+			delete(eventInformerCache, key)
+			eventInformerCacheMu.Unlock()
+			close(entry.stopCh)
+			return
+		}
+		eventInformerCacheMu.Unlock()
+	}
+}
+
+func mergeFieldSelectors(selectors []fields.Selector) string {
+	merged := ""
+	for _, s := range selectors {
+		if s == nil || s.Empty() {
+			continue
+		}
+		if merged != "" {
+			merged += ","
+		}
+		merged += s.String()
+	}
+	return merged
+}
+
+// InvolvedObjectSelector builds the field selector used to scope an event
+// informer to a single involved object, e.g. one pod. Its terms are always in
+// the same order, so the same (kind, uid) always yields the same cache key.
+func InvolvedObjectSelector(kind, uid string) fields.Selector {
+	return fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.uid", uid),
+		fields.OneTermEqualSelector("involvedObject.kind", kind),
+	)
+}
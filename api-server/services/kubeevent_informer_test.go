@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+func TestMergeFieldSelectors(t *testing.T) {
+	cases := []struct {
+		name      string
+		selectors []fields.Selector
+		want      string
+	}{
+		{name: "none", selectors: nil, want: ""},
+		{name: "nil entries skipped", selectors: []fields.Selector{nil}, want: ""},
+		{name: "empty selector skipped", selectors: []fields.Selector{fields.Everything()}, want: ""},
+		{
+			name:      "single",
+			selectors: []fields.Selector{fields.OneTermEqualSelector("involvedObject.uid", "abc")},
+			want:      "involvedObject.uid=abc",
+		},
+		{
+			name: "multiple joined with comma",
+			selectors: []fields.Selector{
+				fields.OneTermEqualSelector("involvedObject.uid", "abc"),
+				fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+			},
+			want: "involvedObject.uid=abc,involvedObject.kind=Pod",
+		},
+		{
+			name: "empty selector mixed with non-empty is skipped",
+			selectors: []fields.Selector{
+				fields.Everything(),
+				fields.OneTermEqualSelector("involvedObject.uid", "abc"),
+			},
+			want: "involvedObject.uid=abc",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := mergeFieldSelectors(c.selectors); got != c.want {
+				t.Errorf("mergeFieldSelectors() = %q, want %q", got, c.want)
+			}
+		})
+	}
+}
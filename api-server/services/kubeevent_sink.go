@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/bentoml/yatai/api-server/services/kubeevent_sink"
+)
+
+// KubeEventSinkManager fans kube events out to whatever sinks were
+// registered via InitKubeEventSinks, which EnsureKubeEventSinks calls on
+// first use rather than at process start. Dispatching to an
+// unconfigured manager (zero sinks) is a cheap no-op, so callers do not need
+// to special-case the "no sinks configured" deployment.
+var KubeEventSinkManager = kubeevent_sink.NewManager()
+
+// InitKubeEventSinks builds and registers the sinks described by cfg against
+// KubeEventSinkManager. EnsureKubeEventSinks calls it once, after loading the
+// config, the first time a cluster-wide collector starts.
+func InitKubeEventSinks(cfg kubeevent_sink.Config) error {
+	return KubeEventSinkManager.RegisterFromConfig(cfg)
+}
+
+var kubeEventSinksInitOnce sync.Once
+
+// EnsureKubeEventSinks makes sure the sinks described by the process's kube
+// event sink config have been registered against KubeEventSinkManager,
+// loading that config the first time it runs. It is the lazy stand-in for a
+// process-boot InitKubeEventSinks call in this series:
+// StartClusterWideCollector is the one place actually reachable at runtime,
+// so sink registration piggybacks on the same on-demand start as the history
+// collector rather than depending on an external boot step that never runs.
+func EnsureKubeEventSinks(ctx context.Context) {
+	kubeEventSinksInitOnce.Do(func() {
+		cfg, err := GetKubeEventSinkConfig(ctx)
+		if err != nil {
+			logrus.WithError(err).Error("kube event sinks: failed to load config")
+			return
+		}
+		if err := InitKubeEventSinks(cfg); err != nil {
+			logrus.WithError(err).Error("kube event sinks: failed to register sinks")
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package kubeevent_sink
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ChannelEvent is what ChannelSink publishes to its subscribers.
+type ChannelEvent struct {
+	Cluster string
+	Op      EventOp
+	Event   *corev1.Event
+}
+
+// ChannelSink publishes every event to a buffered Go channel so that other
+// in-process Yatai services (e.g. the deployment status reconciler) can
+// subscribe without going through the websocket API or an external sink.
+// It never blocks: if the channel is full, the oldest buffered event is
+// dropped to make room, matching Manager's own backpressure policy.
+type ChannelSink struct {
+	name string
+	ch   chan ChannelEvent
+}
+
+func NewChannelSink(name string, bufferSize int) *ChannelSink {
+	if bufferSize <= 0 {
+		bufferSize = defaultQueueSize
+	}
+	return &ChannelSink{name: name, ch: make(chan ChannelEvent, bufferSize)}
+}
+
+func (s *ChannelSink) Name() string {
+	return s.name
+}
+
+// Subscribe returns the channel new events are published to. Multiple
+// subscribers are not supported; wrap with your own fan-out if needed.
+func (s *ChannelSink) Subscribe() <-chan ChannelEvent {
+	return s.ch
+}
+
+func (s *ChannelSink) Send(clusterName string, op EventOp, event *corev1.Event) error {
+	ce := ChannelEvent{Cluster: clusterName, Op: op, Event: event}
+
+	select {
+	case s.ch <- ce:
+	default:
+		select {
+		case <-s.ch:
+		default:
+		}
+		select {
+		case s.ch <- ce:
+		default:
+		}
+	}
+
+	return nil
+}
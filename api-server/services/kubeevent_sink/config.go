@@ -0,0 +1,41 @@
+package kubeevent_sink
+
+import "time"
+
+// Config is the top-level YAML configuration for the event sink subsystem,
+// loaded once by services.EnsureKubeEventSinks, the first time a cluster-wide
+// collector starts, and passed to Manager.RegisterFromConfig.
+type Config struct {
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig describes one configured sink. Only the fields relevant to
+// Type are read; the rest are ignored.
+type SinkConfig struct {
+	// Name identifies this sink in logs and metrics.
+	Name string `yaml:"name"`
+	// Type selects the sink implementation: "webhook", "file" or "channel".
+	Type string `yaml:"type"`
+	// Clusters restricts this sink to the named clusters. Empty means all
+	// clusters.
+	Clusters []string `yaml:"clusters"`
+	// QueueSize is the worker queue capacity before the drop-oldest
+	// backpressure policy kicks in. Defaults to defaultQueueSize.
+	QueueSize int `yaml:"queue_size"`
+
+	// Webhook-specific.
+	WebhookURL     string        `yaml:"webhook_url"`
+	WebhookTimeout time.Duration `yaml:"webhook_timeout"`
+
+	// File-specific.
+	FilePath string `yaml:"file_path"`
+}
+
+const (
+	SinkTypeWebhook = "webhook"
+	SinkTypeFile    = "file"
+	SinkTypeChannel = "channel"
+
+	defaultQueueSize      = 256
+	defaultWebhookTimeout = time.Second * 5
+)
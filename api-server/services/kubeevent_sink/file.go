@@ -0,0 +1,51 @@
+package kubeevent_sink
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// FileSink appends each event as one JSON line to a file on disk.
+type FileSink struct {
+	name string
+	path string
+
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFileSink(name, path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, errors.Wrapf(err, "open event sink file %s", path)
+	}
+	return &FileSink{name: name, path: path, file: f}, nil
+}
+
+func (s *FileSink) Name() string {
+	return s.name
+}
+
+func (s *FileSink) Send(clusterName string, op EventOp, event *corev1.Event) error {
+	line, err := json.Marshal(webhookLine{Cluster: clusterName, Op: op, Event: event})
+	if err != nil {
+		return errors.Wrap(err, "marshal file sink line")
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(line); err != nil {
+		return errors.Wrapf(err, "write event sink file %s", s.path)
+	}
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,139 @@
+package kubeevent_sink
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// job is one unit of work queued for a sink's worker goroutine.
+type job struct {
+	clusterName string
+	op          EventOp
+	event       *corev1.Event
+}
+
+// workerSink pairs a Sink with its own buffered queue and worker goroutine,
+// so a slow or unreachable sink (e.g. a webhook that's timing out) cannot
+// stall delivery to any other sink.
+type workerSink struct {
+	sink     Sink
+	clusters map[string]struct{} // empty means "all clusters"
+	queue    chan job
+	dropped  int64
+}
+
+func (w *workerSink) wantsCluster(clusterName string) bool {
+	if len(w.clusters) == 0 {
+		return true
+	}
+	_, ok := w.clusters[clusterName]
+	return ok
+}
+
+func (w *workerSink) enqueue(j job) {
+	select {
+	case w.queue <- j:
+		return
+	default:
+	}
+
+	// Drop-oldest: make room for the newest event rather than blocking the
+	// informer callback that's dispatching it.
+	select {
+	case <-w.queue:
+		atomic.AddInt64(&w.dropped, 1)
+	default:
+	}
+	select {
+	case w.queue <- j:
+	default:
+		atomic.AddInt64(&w.dropped, 1)
+	}
+}
+
+func (w *workerSink) run() {
+	for j := range w.queue {
+		if err := w.sink.Send(j.clusterName, j.op, j.event); err != nil {
+			logrus.WithError(err).Warnf("kube event sink %q failed to send event", w.sink.Name())
+		}
+	}
+}
+
+// Manager fans out kube events to a set of registered sinks, each
+// on its own backpressure-aware worker queue.
+type Manager struct {
+	workers []*workerSink
+}
+
+// NewManager returns an empty Manager. Use RegisterFromConfig to populate it
+// from YAML, or Register to add sinks built programmatically.
+func NewManager() *Manager {
+	return &Manager{}
+}
+
+// Register adds a sink to the manager, restricted to the given clusters (or
+// every cluster if clusters is empty), and starts its worker goroutine.
+func (m *Manager) Register(sink Sink, clusters []string, queueSize int) {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+
+	clusterSet := make(map[string]struct{}, len(clusters))
+	for _, c := range clusters {
+		clusterSet[c] = struct{}{}
+	}
+
+	w := &workerSink{sink: sink, clusters: clusterSet, queue: make(chan job, queueSize)}
+	m.workers = append(m.workers, w)
+	go w.run()
+}
+
+// RegisterFromConfig builds and registers every sink described by cfg.
+func (m *Manager) RegisterFromConfig(cfg Config) error {
+	for _, sc := range cfg.Sinks {
+		var sink Sink
+		switch sc.Type {
+		case SinkTypeWebhook:
+			sink = NewWebhookSink(sc.Name, sc.WebhookURL, sc.WebhookTimeout)
+		case SinkTypeFile:
+			fileSink, err := NewFileSink(sc.Name, sc.FilePath)
+			if err != nil {
+				return errors.Wrapf(err, "build file sink %q", sc.Name)
+			}
+			sink = fileSink
+		case SinkTypeChannel:
+			sink = NewChannelSink(sc.Name, sc.QueueSize)
+		default:
+			return errors.Errorf("unknown kube event sink type %q for sink %q", sc.Type, sc.Name)
+		}
+
+		m.Register(sink, sc.Clusters, sc.QueueSize)
+	}
+
+	return nil
+}
+
+// Dispatch fans out a single event to every registered sink that
+// watches clusterName. It never blocks on a slow sink: each sink has its own
+// buffered queue and drops its oldest pending event on overflow.
+func (m *Manager) Dispatch(clusterName string, op EventOp, event *corev1.Event) {
+	for _, w := range m.workers {
+		if !w.wantsCluster(clusterName) {
+			continue
+		}
+		w.enqueue(job{clusterName: clusterName, op: op, event: event})
+	}
+}
+
+// DroppedCounts returns the number of events dropped per sink name so far,
+// for exposing as a metric.
+func (m *Manager) DroppedCounts() map[string]int64 {
+	counts := make(map[string]int64, len(m.workers))
+	for _, w := range m.workers {
+		counts[w.sink.Name()] = atomic.LoadInt64(&w.dropped)
+	}
+	return counts
+}
@@ -0,0 +1,27 @@
+package kubeevent_sink
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestWorkerSinkEnqueueDropsOldestOnOverflow(t *testing.T) {
+	w := &workerSink{queue: make(chan job, 2)}
+
+	w.enqueue(job{clusterName: "c1"})
+	w.enqueue(job{clusterName: "c2"})
+	if dropped := atomic.LoadInt64(&w.dropped); dropped != 0 {
+		t.Fatalf("expected no drops while queue has room, got %d", dropped)
+	}
+
+	w.enqueue(job{clusterName: "c3"})
+	if dropped := atomic.LoadInt64(&w.dropped); dropped != 1 {
+		t.Fatalf("expected 1 drop after overflowing a full queue, got %d", dropped)
+	}
+
+	first := <-w.queue
+	second := <-w.queue
+	if first.clusterName != "c2" || second.clusterName != "c3" {
+		t.Fatalf("expected oldest job (c1) to be dropped, got %q then %q", first.clusterName, second.clusterName)
+	}
+}
@@ -0,0 +1,31 @@
+// Package kubeevent_sink fans Kubernetes events out to external
+// observability backends (webhooks, files, or other in-process Yatai
+// services) in parallel with the websocket stream served by kubeController.
+package kubeevent_sink
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EventOp is the kind of informer callback that produced an event, mirroring
+// the Add/Update/Delete semantics client-go's ResourceEventHandlerFuncs
+// already uses.
+type EventOp string
+
+const (
+	EventOpAdd    EventOp = "add"
+	EventOpUpdate EventOp = "update"
+	EventOpDelete EventOp = "delete"
+)
+
+// Sink receives every kube event the cluster-wide collector sees for the
+// clusters it is registered for. No deployment or pod filter (such as
+// services.KubeEventService.MakeDeploymentKubeEventFilter) is applied first.
+type Sink interface {
+	// Name identifies the sink in logs and metrics.
+	Name() string
+	// Send delivers a single event. Implementations should not block the
+	// caller for long; Manager already runs each sink on its own worker
+	// queue so a slow Send only risks that sink's own backlog.
+	Send(clusterName string, op EventOp, event *corev1.Event) error
+}
@@ -0,0 +1,62 @@
+package kubeevent_sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// webhookLine is the JSON-lines payload POSTed to a WebhookSink's URL, one
+// object per request.
+type webhookLine struct {
+	Cluster string        `json:"cluster"`
+	Op      EventOp       `json:"op"`
+	Event   *corev1.Event `json:"event"`
+}
+
+// WebhookSink POSTs each event as a single JSON object to a configured URL,
+// for shipping BentoML deployment events into Loki, Elasticsearch or an
+// alertmanager-style webhook receiver.
+type WebhookSink struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(name, url string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	return &WebhookSink{
+		name:   name,
+		url:    url,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *WebhookSink) Name() string {
+	return s.name
+}
+
+func (s *WebhookSink) Send(clusterName string, op EventOp, event *corev1.Event) error {
+	body, err := json.Marshal(webhookLine{Cluster: clusterName, Op: op, Event: event})
+	if err != nil {
+		return errors.Wrap(err, "marshal webhook payload")
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "post webhook")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return errors.Errorf("webhook %s returned status %d", s.url, resp.StatusCode)
+	}
+
+	return nil
+}
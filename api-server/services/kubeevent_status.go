@@ -0,0 +1,206 @@
+package services
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// EventSeverity classifies an EventStatus by how urgently it should be
+// surfaced to a user watching a deployment or pod.
+type EventSeverity string
+
+const (
+	EventSeverityInfo    EventSeverity = "info"
+	EventSeverityWarning EventSeverity = "warning"
+	EventSeverityError   EventSeverity = "error"
+)
+
+// wellKnownEventReasonSeverities maps the Kubernetes event Reasons we care
+// about in the Yatai UI to a severity. Reasons not listed here fall back to
+// EventSeverityInfo for Normal events and EventSeverityWarning for Warning
+// events.
+var wellKnownEventReasonSeverities = map[string]EventSeverity{
+	"FailedScheduling": EventSeverityError,
+	"BackOff":          EventSeverityWarning,
+	"Unhealthy":        EventSeverityWarning,
+	"OOMKilled":        EventSeverityError,
+	"Pulling":          EventSeverityInfo,
+	"Pulled":           EventSeverityInfo,
+}
+
+// ClassifyEventReason returns the severity Yatai assigns to a given
+// (type, reason) pair coming from the Kubernetes event stream, where typ is
+// one of corev1.EventTypeNormal / corev1.EventTypeWarning.
+func ClassifyEventReason(typ, reason string) EventSeverity {
+	return classifyEventReason(typ, reason)
+}
+
+func classifyEventReason(typ, reason string) EventSeverity {
+	if severity, ok := wellKnownEventReasonSeverities[reason]; ok {
+		return severity
+	}
+	if typ == corev1.EventTypeWarning {
+		return EventSeverityWarning
+	}
+	return EventSeverityInfo
+}
+
+// EventStatus is the typed, UI-friendly projection of a corev1.Event that
+// Yatai streams over the kube-events websocket, in place of raw event
+// objects. UID is the Event object's own identity, not the involved object's:
+// a single pod routinely produces several distinct events (FailedScheduling,
+// then Pulling, then BackOff, ...) sharing one InvolvedUID, and those must be
+// tracked as separate entries rather than collide into one.
+type EventStatus struct {
+	Name            string        `json:"name"`
+	UID             types.UID     `json:"uid"`
+	Namespace       string        `json:"namespace"`
+	Type            string        `json:"type"`
+	Reason          string        `json:"reason"`
+	Severity        EventSeverity `json:"severity"`
+	InvolvedKind    string        `json:"involved_kind"`
+	InvolvedUID     types.UID     `json:"involved_uid"`
+	InvolvedName    string        `json:"involved_name"`
+	FirstSeen       metav1.Time   `json:"first_seen"`
+	LastSeen        metav1.Time   `json:"last_seen"`
+	Count           int32         `json:"count"`
+	Message         string        `json:"message"`
+	ResourceVersion string        `json:"resource_version"`
+}
+
+// NewEventStatusFromEvent converts a raw corev1.Event into the typed
+// EventStatus payload sent to websocket clients.
+func NewEventStatusFromEvent(event *corev1.Event) EventStatus {
+	firstSeen := event.FirstTimestamp
+	lastSeen := event.LastTimestamp
+	if lastSeen.IsZero() {
+		lastSeen = event.FirstTimestamp
+	}
+	if !event.EventTime.IsZero() {
+		lastSeen.Time = event.EventTime.Time
+	}
+
+	return EventStatus{
+		Name:            event.Name,
+		UID:             event.UID,
+		Namespace:       event.Namespace,
+		Type:            event.Type,
+		Reason:          event.Reason,
+		Severity:        classifyEventReason(event.Type, event.Reason),
+		InvolvedKind:    event.InvolvedObject.Kind,
+		InvolvedUID:     event.InvolvedObject.UID,
+		InvolvedName:    event.InvolvedObject.Name,
+		FirstSeen:       firstSeen,
+		LastSeen:        lastSeen,
+		Count:           event.Count,
+		Message:         event.Message,
+		ResourceVersion: event.ResourceVersion,
+	}
+}
+
+// equal reports whether two EventStatus values describe the same observed
+// state of an event, comparing the same fields the API server uses to
+// version an object: UID, Name, Namespace and ResourceVersion.
+func (s EventStatus) equal(other EventStatus) bool {
+	return s.UID == other.UID &&
+		s.Name == other.Name &&
+		s.Namespace == other.Namespace &&
+		s.ResourceVersion == other.ResourceVersion
+}
+
+// EventDiffKind describes how an EventStatus changed between two
+// consecutive snapshots of the informer cache.
+type EventDiffKind string
+
+const (
+	EventDiffKindAdded   EventDiffKind = "added"
+	EventDiffKindUpdated EventDiffKind = "updated"
+	EventDiffKindDeleted EventDiffKind = "deleted"
+)
+
+// EventDiff is a single change to report to a websocket client: either a
+// new/changed EventStatus, or the UID of one that disappeared from the
+// informer cache.
+type EventDiff struct {
+	Kind   EventDiffKind `json:"kind"`
+	Status *EventStatus  `json:"status,omitempty"`
+	UID    types.UID     `json:"uid,omitempty"`
+}
+
+// EventDiffEnvelope wraps a batch of EventDiffs with whether they were
+// replayed from KubeEventHistoryStore or observed live off the informer, so the
+// UI can render a divider between "what happened before you opened this" and
+// "what's happening now".
+type EventDiffEnvelope struct {
+	Replayed bool        `json:"replayed"`
+	Diffs    []EventDiff `json:"diffs"`
+}
+
+// EventStatusTracker keeps the last snapshot of EventStatus values seen on a
+// single websocket connection, keyed by the Event object's own UID (not the
+// involved object's, since one pod can have several live events at once), so
+// that repeated informer callbacks can be turned into a small
+// Added/Updated/Deleted diff instead of re-sending the full event list every
+// time.
+type EventStatusTracker struct {
+	mu   sync.Mutex
+	seen map[types.UID]EventStatus
+}
+
+// NewEventStatusTracker returns an empty tracker, one of which should be
+// created per websocket connection.
+func NewEventStatusTracker() *EventStatusTracker {
+	return &EventStatusTracker{seen: make(map[types.UID]EventStatus)}
+}
+
+// Seed marks statuses as already seen without producing any diffs for them,
+// so a tracker can be primed with events a caller already reported by other
+// means (e.g. a history replay) before its first call to Diff.
+func (t *EventStatusTracker) Seed(statuses []EventStatus) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, status := range statuses {
+		t.seen[status.UID] = status
+	}
+}
+
+// Diff compares the given events against what this tracker last saw and
+// returns the Added/Updated/Deleted diffs, updating its internal snapshot in
+// the process. An event sharing the UID of one already seen is compared with
+// EventStatus.equal to decide between a noop and an Updated diff.
+func (t *EventStatusTracker) Diff(events []*corev1.Event) []EventDiff {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := make(map[types.UID]EventStatus, len(events))
+	diffs := make([]EventDiff, 0)
+
+	for _, event := range events {
+		status := NewEventStatusFromEvent(event)
+		current[status.UID] = status
+
+		prev, ok := t.seen[status.UID]
+		switch {
+		case !ok:
+			statusCopy := status
+			diffs = append(diffs, EventDiff{Kind: EventDiffKindAdded, Status: &statusCopy})
+		case !prev.equal(status):
+			statusCopy := status
+			diffs = append(diffs, EventDiff{Kind: EventDiffKindUpdated, Status: &statusCopy})
+		}
+	}
+
+	for uid := range t.seen {
+		if _, ok := current[uid]; !ok {
+			diffs = append(diffs, EventDiff{Kind: EventDiffKindDeleted, UID: uid})
+		}
+	}
+
+	t.seen = current
+
+	return diffs
+}
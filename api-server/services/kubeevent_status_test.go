@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClassifyEventReason(t *testing.T) {
+	cases := []struct {
+		typ, reason string
+		want        EventSeverity
+	}{
+		{corev1.EventTypeWarning, "FailedScheduling", EventSeverityError},
+		{corev1.EventTypeWarning, "BackOff", EventSeverityWarning},
+		{corev1.EventTypeWarning, "SomethingUnlisted", EventSeverityWarning},
+		{corev1.EventTypeNormal, "Pulled", EventSeverityInfo},
+		{corev1.EventTypeNormal, "SomethingUnlisted", EventSeverityInfo},
+	}
+
+	for _, c := range cases {
+		if got := ClassifyEventReason(c.typ, c.reason); got != c.want {
+			t.Errorf("ClassifyEventReason(%q, %q) = %q, want %q", c.typ, c.reason, got, c.want)
+		}
+	}
+}
+
+func newTestEvent(uid types.UID, resourceVersion string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:      metav1.ObjectMeta{Name: "evt-" + string(uid), Namespace: "default", UID: uid},
+		InvolvedObject:  corev1.ObjectReference{UID: uid, Kind: "Pod", Name: "pod-" + string(uid)},
+		Type:            corev1.EventTypeNormal,
+		Reason:          "Pulled",
+		ResourceVersion: resourceVersion,
+	}
+}
+
+func TestEventStatusTrackerDiff(t *testing.T) {
+	tracker := NewEventStatusTracker()
+
+	diffs := tracker.Diff([]*corev1.Event{newTestEvent("a", "1"), newTestEvent("b", "1")})
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 added diffs, got %d", len(diffs))
+	}
+	for _, d := range diffs {
+		if d.Kind != EventDiffKindAdded {
+			t.Errorf("expected Added, got %s", d.Kind)
+		}
+	}
+
+	diffs = tracker.Diff([]*corev1.Event{newTestEvent("a", "2"), newTestEvent("b", "1")})
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 updated diff, got %d", len(diffs))
+	}
+	if diffs[0].Kind != EventDiffKindUpdated || diffs[0].Status.UID != "a" {
+		t.Errorf("expected Updated diff for uid a, got %+v", diffs[0])
+	}
+
+	diffs = tracker.Diff([]*corev1.Event{newTestEvent("a", "2")})
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 deleted diff, got %d", len(diffs))
+	}
+	if diffs[0].Kind != EventDiffKindDeleted || diffs[0].UID != "b" {
+		t.Errorf("expected Deleted diff for uid b, got %+v", diffs[0])
+	}
+}
+
+func TestEventStatusTrackerSeed(t *testing.T) {
+	tracker := NewEventStatusTracker()
+	seeded := NewEventStatusFromEvent(newTestEvent("a", "1"))
+	tracker.Seed([]EventStatus{seeded})
+
+	diffs := tracker.Diff([]*corev1.Event{newTestEvent("a", "1")})
+	if len(diffs) != 0 {
+		t.Fatalf("expected no diffs for an already-seeded, unchanged event, got %+v", diffs)
+	}
+}
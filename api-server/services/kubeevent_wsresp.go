@@ -0,0 +1,14 @@
+package services
+
+import "github.com/bentoml/yatai-schemas/schemasv1"
+
+// WsRespTypeEventDiff and WsRespTypePing mark kube-events websocket payload
+// kinds that are specific to this feature: a batch of EventDiffs, and a
+// server-initiated keepalive frame that clients are expected to answer with a
+// "pong" text frame within a few intervals or the server tears the connection
+// down. They mirror the schemasv1.WsRespTypeXXX constants but live here until
+// yatai-schemas vendors them alongside the others.
+const (
+	WsRespTypeEventDiff schemasv1.WsRespType = "event_diff"
+	WsRespTypePing      schemasv1.WsRespType = "ping"
+)